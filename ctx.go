@@ -0,0 +1,76 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// ctxKey namespaces the values this package stores on a context.Context,
+// so it doesn't collide with keys set by other packages.
+type ctxKey string
+
+const (
+	traceIDKey ctxKey = "trace_id"
+	spanIDKey  ctxKey = "span_id"
+)
+
+// WithTraceID returns a copy of ctx carrying traceID, which InfoCtx and
+// friends will attach to every log line written with that context.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// WithSpanID returns a copy of ctx carrying spanID, which InfoCtx and
+// friends will attach to every log line written with that context.
+func WithSpanID(ctx context.Context, spanID string) context.Context {
+	return context.WithValue(ctx, spanIDKey, spanID)
+}
+
+// ctxFields extracts the trace/span IDs stashed on ctx (if any) as zap
+// fields so they show up alongside whatever fields the caller passed in.
+func ctxFields(ctx context.Context) []zap.Field {
+	fields := make([]zap.Field, 0, 2)
+	if v, ok := ctx.Value(traceIDKey).(string); ok && v != "" {
+		fields = append(fields, zap.String("trace_id", v))
+	}
+	if v, ok := ctx.Value(spanIDKey).(string); ok && v != "" {
+		fields = append(fields, zap.String("span_id", v))
+	}
+	return fields
+}
+
+func InfoCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if Logger == nil {
+		Default()
+	}
+	Logger.Info(msg, append(ctxFields(ctx), fields...)...)
+}
+
+func DebugCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if Logger == nil {
+		Default()
+	}
+	Logger.Debug(msg, append(ctxFields(ctx), fields...)...)
+}
+
+func WarnCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if Logger == nil {
+		Default()
+	}
+	Logger.Warn(msg, append(ctxFields(ctx), fields...)...)
+}
+
+func ErrorCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if Logger == nil {
+		Default()
+	}
+	Logger.Error(msg, append(ctxFields(ctx), fields...)...)
+}
+
+func FatalCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if Logger == nil {
+		Default()
+	}
+	Logger.Fatal(msg, append(ctxFields(ctx), fields...)...)
+}
@@ -0,0 +1,181 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// hybridWriteSyncer wraps a lumberjack.Logger and additionally forces a
+// Rotate() whenever the configured time boundary is crossed, so the file
+// rolls on *either* size or time, whichever comes first.
+type hybridWriteSyncer struct {
+	mu         sync.Mutex
+	lj         *lumberjack.Logger
+	filePath   string
+	interval   time.Duration
+	bucket     time.Time
+	maxBackups int
+	maxAge     int
+}
+
+func newHybridWriteSyncer(filename string, maxSize, maxBackups, maxAge int, cfg *RollingConfig) *hybridWriteSyncer {
+	interval := time.Hour
+	if cfg != nil {
+		interval = cfg.rotationInterval()
+		if cfg.MaxSizePerFile > 0 {
+			maxSize = cfg.MaxSizePerFile
+		}
+	}
+	return &hybridWriteSyncer{
+		lj: &lumberjack.Logger{
+			Filename:   filename,
+			MaxSize:    maxSize, //MB
+			LocalTime:  true,
+			MaxBackups: maxBackups,
+			MaxAge:     maxAge,
+			// Compress is intentionally left false: lumberjack compresses
+			// asynchronously in a background goroutine kicked off by
+			// Rotate(), and renameLatestBackup runs right after Rotate()
+			// returns, racing that goroutine for the same file. Turning
+			// this on would silently compress some backups and not
+			// others depending on which one wins.
+		},
+		filePath:   filename,
+		interval:   interval,
+		bucket:     time.Now().Truncate(interval),
+		maxBackups: maxBackups,
+		maxAge:     maxAge,
+	}
+}
+
+func (h *hybridWriteSyncer) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if bucket := time.Now().Truncate(h.interval); bucket.After(h.bucket) {
+		h.bucket = bucket
+		if err := h.rotateWithDateSuffix(); err != nil {
+			fmt.Printf("hybrid rolling: failed to rotate on time boundary: %v\n", err)
+		}
+	}
+	return h.lj.Write(p)
+}
+
+// Sync is a no-op: lumberjack buffers nothing internally (it writes
+// straight through to the open file), matching what zapcore.AddSync would
+// have given it automatically. Closing the file here would pull it out
+// from under a process that's still writing, the way zap's own
+// `defer Logger.Sync()` pattern does after every flush.
+func (h *hybridWriteSyncer) Sync() error {
+	return nil
+}
+
+// Close closes the underlying file. Callers that want a clean shutdown
+// should call this explicitly rather than relying on Sync.
+func (h *hybridWriteSyncer) Close() error {
+	return h.lj.Close()
+}
+
+// rotateWithDateSuffix rolls the current file via lumberjack, then renames
+// the backup lumberjack just created to a date-suffix name modeled on the
+// pattern RotateLogs uses for date rolling (see datedBackupSuffix), so
+// hybrid and date backups look alike on disk. Renaming takes the backup out of lumberjack's own
+// naming scheme, so lumberjack's MaxBackups/MaxAge can no longer find it
+// to prune it; enforceRetention reimplements that cleanup against our own
+// naming pattern instead.
+func (h *hybridWriteSyncer) rotateWithDateSuffix() error {
+	if err := h.lj.Rotate(); err != nil {
+		return err
+	}
+	if err := renameLatestBackup(h.filePath); err != nil {
+		return err
+	}
+	enforceRetention(h.filePath, h.maxBackups, h.maxAge)
+	return nil
+}
+
+// datedBackupSuffix extends dateRollingSuffix's ".%Y%m%d" pattern with a
+// time-of-day component, since RollingByHybrid rotates sub-daily (e.g. the
+// request's own "0 * * * *" hourly example): day-only granularity would
+// make every rotation within the same day rename to the same target path,
+// silently clobbering all but the last one.
+func datedBackupSuffix(t time.Time) string {
+	return "." + t.Format("20060102-150405")
+}
+
+func renameLatestBackup(filePath string) error {
+	dir := filepath.Dir(filePath)
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext)
+	matches, err := filepath.Glob(filepath.Join(dir, prefix+"-*"))
+	if err != nil || len(matches) == 0 {
+		return err
+	}
+	sort.Strings(matches)
+	latest := matches[len(matches)-1]
+	if _, err := os.Stat(latest); err != nil {
+		return err
+	}
+	dated := uniquePath(filepath.Join(dir, prefix+datedBackupSuffix(time.Now())+ext), strings.HasSuffix(latest, ".gz"))
+	return os.Rename(latest, dated)
+}
+
+// uniquePath returns path (with a trailing ".gz" if gz is set) if nothing
+// is there yet, otherwise inserts an incrementing counter before path's
+// extension until it finds a free name, so two rotations landing on the
+// same second never clobber each other.
+func uniquePath(path string, gz bool) string {
+	ext := filepath.Ext(path)
+	withoutExt := strings.TrimSuffix(path, ext)
+	for i := 0; ; i++ {
+		candidate := path
+		if i > 0 {
+			candidate = fmt.Sprintf("%s-%d%s", withoutExt, i, ext)
+		}
+		if gz {
+			candidate += ".gz"
+		}
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// enforceRetention prunes renamed hybrid backups by maxBackups count and
+// maxAge (days), mirroring what lumberjack's own cleanup would have done
+// had the backups still matched its naming pattern.
+func enforceRetention(filePath string, maxBackups, maxAge int) {
+	dir := filepath.Dir(filePath)
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext)
+	matches, err := filepath.Glob(filepath.Join(dir, prefix+".*"+ext+"*"))
+	if err != nil {
+		return
+	}
+	sort.Strings(matches)
+
+	var kept []string
+	cutoff := time.Now().AddDate(0, 0, -maxAge)
+	for _, m := range matches {
+		if maxAge > 0 {
+			if info, statErr := os.Stat(m); statErr == nil && info.ModTime().Before(cutoff) {
+				os.Remove(m)
+				continue
+			}
+		}
+		kept = append(kept, m)
+	}
+	if maxBackups > 0 && len(kept) > maxBackups {
+		for _, m := range kept[:len(kept)-maxBackups] {
+			os.Remove(m)
+		}
+	}
+}
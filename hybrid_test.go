@@ -0,0 +1,86 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRenameLatestBackupUsesDateDotSuffix(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "app.log")
+	backup := filepath.Join(dir, "app-2023-01-15T10-00-00.000.log")
+	if err := os.WriteFile(backup, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := renameLatestBackup(filePath); err != nil {
+		t.Fatalf("renameLatestBackup: %v", err)
+	}
+
+	want := filepath.Join(dir, "app"+datedBackupSuffix(time.Now())+".log")
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("expected renamed backup at %s: %v", want, err)
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Fatalf("expected original lumberjack-named backup to be gone, got err=%v", err)
+	}
+}
+
+func TestRenameLatestBackupHandlesSameSecondCollisions(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "app.log")
+
+	first := filepath.Join(dir, "app-2023-01-15T10-00-00.000.log")
+	if err := os.WriteFile(first, []byte("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := renameLatestBackup(filePath); err != nil {
+		t.Fatalf("first renameLatestBackup: %v", err)
+	}
+
+	// A second rotation landing in the same second as the first (the
+	// common case for hourly/sub-day RollingByHybrid boundaries) must not
+	// silently overwrite it.
+	second := filepath.Join(dir, "app-2023-01-15T10-05-00.000.log")
+	if err := os.WriteFile(second, []byte("second"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := renameLatestBackup(filePath); err != nil {
+		t.Fatalf("second renameLatestBackup: %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "app.*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected both rotations to survive as distinct files, got %d: %v", len(matches), matches)
+	}
+}
+
+func TestEnforceRetentionPrunesOldestBeyondMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "app.log")
+	for _, d := range []string{"20230101", "20230102", "20230103", "20230104"} {
+		if err := os.WriteFile(filepath.Join(dir, "app."+d+".log"), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	enforceRetention(filePath, 2, 0)
+
+	matches, err := filepath.Glob(filepath.Join(dir, "app.*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 backups kept, got %d: %v", len(matches), matches)
+	}
+	for _, want := range []string{"app.20230103.log", "app.20230104.log"} {
+		if _, err := os.Stat(filepath.Join(dir, want)); err != nil {
+			t.Errorf("expected %s to survive retention: %v", want, err)
+		}
+	}
+}
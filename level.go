@@ -0,0 +1,43 @@
+package log
+
+import (
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// SetLevel changes the level of the file and stdout cores built by Init/
+// SizeRolling/DateRolling/HybridRolling/Default at runtime, no restart
+// required. Registered sinks keep the level they were registered with.
+func SetLevel(logLevel string) {
+	atomicLevel.SetLevel(logLv(logLevel))
+}
+
+// GetLevel returns the currently active log level.
+func GetLevel() string {
+	return atomicLevel.Level().String()
+}
+
+// LevelHandler returns an http.Handler that serves and updates the current
+// log level, e.g. mounted at /debug/level. GET returns the current level as
+// JSON, PUT with a JSON body such as {"level":"debug"} changes it.
+func LevelHandler() http.Handler {
+	return atomicLevel
+}
+
+// WatchSIGHUP re-reads the log level from envVar every time the process
+// receives SIGHUP, so operators can flip between DEBUG and INFO without
+// restarting the service. It returns immediately; the watch runs in its
+// own goroutine for the lifetime of the process.
+func WatchSIGHUP(envVar string) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for range sigCh {
+			if lv := os.Getenv(envVar); lv != "" {
+				SetLevel(lv)
+			}
+		}
+	}()
+}
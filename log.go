@@ -17,20 +17,33 @@ const (
 	dateRollingSuffix = ".%Y%m%d"
 	RollingBySize     = 0
 	RollingByDate     = 1
+	RollingByHybrid   = 2
+)
+
+// log output format, passed to Init/SizeRolling/DateRolling
+const (
+	FormatText = 0
+	FormatJSON = 1
 )
 
 var (
 	Logger *zap.Logger
 	Sugar  *zap.SugaredLogger
 	once   sync.Once
+
+	// atomicLevel backs the file/stdout cores so the log level can be
+	// changed at runtime via SetLevel, without rebuilding the logger.
+	atomicLevel = zap.NewAtomicLevel()
 )
 
-func Init(filename string, logLevel string, maxSize, maxBackups, maxAge int, rollingBy int, stdout ...bool) {
+func Init(filename string, logLevel string, maxSize, maxBackups, maxAge int, rollingBy int, format int, rollingCfg *RollingConfig, samplingCfg *SamplingConfig, sinkNames []string, stdout ...bool) {
 	switch rollingBy {
 	case RollingBySize:
-		SizeRolling(filename, logLevel, maxSize, maxBackups, maxAge, stdout...)
+		SizeRolling(filename, logLevel, maxSize, maxBackups, maxAge, format, samplingCfg, sinkNames, stdout...)
 	case RollingByDate:
-		DateRolling(filename, logLevel, maxBackups, maxAge, stdout...)
+		DateRolling(filename, logLevel, maxBackups, maxAge, format, samplingCfg, sinkNames, stdout...)
+	case RollingByHybrid:
+		HybridRolling(filename, logLevel, maxSize, maxBackups, maxAge, format, rollingCfg, samplingCfg, sinkNames, stdout...)
 	}
 }
 
@@ -63,24 +76,25 @@ func logLv(logLevel string) zapcore.Level {
 	return level
 }
 
-func DateRolling(filename string, logLevel string, maxBackups, maxAge int, stdout ...bool) {
+func DateRolling(filename string, logLevel string, maxBackups, maxAge int, format int, samplingCfg *SamplingConfig, sinkNames []string, stdout ...bool) {
 	rotateLogs, err := RotateLogs(filename, uint(maxBackups), maxAge)
 	if err != nil {
 		panic(err)
 	}
-	level := logLv(logLevel)
+	atomicLevel.SetLevel(logLv(logLevel))
 	cores := make([]zapcore.Core, 0)
 	fileWriterSyncer := zapcore.AddSync(rotateLogs)
-	logCore(fileWriterSyncer, level, &cores)
-	devCore(stdout, level, &cores)
+	logCore(fileWriterSyncer, format, samplingCfg, &cores)
+	devCore(stdout, &cores)
+	appendSinks(sinkNames, &cores)
 	core := zapcore.NewTee(cores...)
 	//skip one layer of caller tracing
 	Logger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 	Sugar = Logger.Sugar()
 }
 
-func SizeRolling(filename string, logLevel string, maxSize, maxBackups, maxAge int, stdout ...bool) {
-	level := logLv(logLevel)
+func SizeRolling(filename string, logLevel string, maxSize, maxBackups, maxAge int, format int, samplingCfg *SamplingConfig, sinkNames []string, stdout ...bool) {
+	atomicLevel.SetLevel(logLv(logLevel))
 	cores := make([]zapcore.Core, 0)
 	fileWriterSyncer := zapcore.AddSync(&lumberjack.Logger{
 		Filename:   filename,
@@ -90,28 +104,52 @@ func SizeRolling(filename string, logLevel string, maxSize, maxBackups, maxAge i
 		MaxAge:     maxAge, //Day
 		Compress:   true,   //compress log file
 	})
-	logCore(fileWriterSyncer, level, &cores)
-	devCore(stdout, level, &cores)
+	logCore(fileWriterSyncer, format, samplingCfg, &cores)
+	devCore(stdout, &cores)
+	appendSinks(sinkNames, &cores)
+	core := zapcore.NewTee(cores...)
+	Logger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	Sugar = Logger.Sugar()
+}
+
+// HybridRolling rotates the log file whenever either MaxSize is exceeded or
+// rollingCfg's time boundary is crossed, whichever happens first.
+func HybridRolling(filename string, logLevel string, maxSize, maxBackups, maxAge int, format int, rollingCfg *RollingConfig, samplingCfg *SamplingConfig, sinkNames []string, stdout ...bool) {
+	atomicLevel.SetLevel(logLv(logLevel))
+	cores := make([]zapcore.Core, 0)
+	fileWriterSyncer := zapcore.AddSync(newHybridWriteSyncer(filename, maxSize, maxBackups, maxAge, rollingCfg))
+	logCore(fileWriterSyncer, format, samplingCfg, &cores)
+	devCore(stdout, &cores)
+	appendSinks(sinkNames, &cores)
 	core := zapcore.NewTee(cores...)
 	Logger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 	Sugar = Logger.Sugar()
 }
 
-func logCore(fileWriterSyncer zapcore.WriteSyncer, level zapcore.Level, cores *[]zapcore.Core) {
+func logCore(fileWriterSyncer zapcore.WriteSyncer, format int, samplingCfg *SamplingConfig, cores *[]zapcore.Core) {
 	fileEncoderConfig := zap.NewProductionEncoderConfig()
 	fileEncoderConfig.EncodeTime = timeEncoder
 	fileEncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
-	c := zapcore.NewCore(zapcore.NewConsoleEncoder(fileEncoderConfig), fileWriterSyncer, level)
+	var encoder zapcore.Encoder
+	switch format {
+	case FormatJSON:
+		//structured output for log-aggregation pipelines (ELK, Loki, ...)
+		encoder = zapcore.NewJSONEncoder(fileEncoderConfig)
+	default:
+		encoder = zapcore.NewConsoleEncoder(fileEncoderConfig)
+	}
+	var c zapcore.Core = zapcore.NewCore(encoder, fileWriterSyncer, atomicLevel)
 	//format log output time & uppercase log level
+	c = wrapSampling(c, samplingCfg)
 	*cores = append(*cores, c)
 }
 
-func devCore(stdout []bool, level zapcore.Level, cores *[]zapcore.Core) {
+func devCore(stdout []bool, cores *[]zapcore.Core) {
 	if len(stdout) > 0 && stdout[0] {
 		developmentEncoderConfig := zap.NewDevelopmentEncoderConfig()
 		developmentEncoderConfig.EncodeTime = timeEncoder
 		developmentEncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
-		c := zapcore.NewCore(zapcore.NewConsoleEncoder(developmentEncoderConfig), zapcore.WriteSyncer(os.Stdout), level)
+		c := zapcore.NewCore(zapcore.NewConsoleEncoder(developmentEncoderConfig), zapcore.WriteSyncer(os.Stdout), atomicLevel)
 		*cores = append(*cores, c)
 	}
 }
@@ -141,18 +179,30 @@ func Default() {
 		developmentEncoderConfig.EncodeTime = timeEncoder
 		developmentEncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 		//log default level: info
-		logLevel := zap.InfoLevel
+		atomicLevel.SetLevel(zap.InfoLevel)
 		//check the debug switch
 		debugEnabled := os.Getenv("DEBUG")
 		if len(debugEnabled) > 0 {
-			logLevel = zap.DebugLevel
+			atomicLevel.SetLevel(zap.DebugLevel)
 		}
-		core := zapcore.NewCore(zapcore.NewConsoleEncoder(developmentEncoderConfig), zapcore.WriteSyncer(os.Stdout), logLevel)
+		core := zapcore.NewCore(zapcore.NewConsoleEncoder(developmentEncoderConfig), zapcore.WriteSyncer(os.Stdout), atomicLevel)
 		Logger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 		Sugar = Logger.Sugar()
 	})
 }
 
+// With returns a child logger with the given structured fields attached,
+// for callers that want zap's structured API instead of the Sugar helpers.
+func With(fields ...zap.Field) *zap.Logger {
+	if Logger == nil {
+		Default()
+	}
+	// Logger carries AddCallerSkip(1) to compensate for the Sugar wrapper
+	// frame (log.Info -> Sugar.Info). Callers of With(...).Info(...) don't
+	// go through that wrapper, so undo the extra skip here.
+	return Logger.WithOptions(zap.AddCallerSkip(-1)).With(fields...)
+}
+
 func Info(args ...interface{}) {
 	if Sugar == nil {
 		Default()
@@ -0,0 +1,51 @@
+package log
+
+import "time"
+
+// RollingConfig tunes RollingByHybrid, which rotates the log file whenever
+// either a size threshold or a time boundary is crossed, whichever comes
+// first.
+type RollingConfig struct {
+	// RotationInterval is the time boundary, e.g. time.Hour for hourly
+	// rotation. Ignored if RotationCron is set to an expression parseCron
+	// understands.
+	RotationInterval time.Duration
+	// RotationCron is a cron-style rotation boundary. Only the fixed
+	// hourly, daily and weekly expressions listed in parseCron are
+	// understood; anything else falls back to RotationInterval.
+	RotationCron string
+	// MaxSizePerFile is the size boundary in MB, same unit as lumberjack's
+	// MaxSize.
+	MaxSizePerFile int
+}
+
+// rotationInterval resolves the configured boundary to a concrete
+// time.Duration, preferring RotationCron when it parses.
+func (c *RollingConfig) rotationInterval() time.Duration {
+	if c.RotationCron != "" {
+		if d, ok := parseCron(c.RotationCron); ok {
+			return d
+		}
+	}
+	if c.RotationInterval > 0 {
+		return c.RotationInterval
+	}
+	return time.Hour
+}
+
+// parseCron understands the handful of cron expressions that are actually
+// useful for log rotation ("0 * * * *" hourly, "0 0 * * *" daily) and
+// reports false for anything else rather than pulling in a full cron
+// parser.
+func parseCron(expr string) (time.Duration, bool) {
+	switch expr {
+	case "0 * * * *":
+		return time.Hour, true
+	case "0 0 * * *":
+		return 24 * time.Hour, true
+	case "0 0 * * 0":
+		return 7 * 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type checkCountingCore struct {
+	zapcore.Core
+	checks int
+}
+
+func (c *checkCountingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	c.checks++
+	return c.Core.Check(ent, ce)
+}
+
+func TestRateLimitedCoreDelegatesCheckToWrappedCore(t *testing.T) {
+	wrapped := &checkCountingCore{Core: zapcore.NewNopCore()}
+	rl := &rateLimitedCore{Core: wrapped, limiter: newTemplateRateLimiter(100)}
+
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Caller: zapcore.NewEntryCaller(0, "x.go", 1, true)}
+	rl.Check(ent, &zapcore.CheckedEntry{})
+
+	if wrapped.checks != 1 {
+		t.Fatalf("expected wrapped core's Check to run exactly once, got %d", wrapped.checks)
+	}
+}
+
+func TestRateLimitedCoreKeysOnCallerNotMessage(t *testing.T) {
+	rl := &rateLimitedCore{Core: zapcore.NewNopCore(), limiter: newTemplateRateLimiter(1)}
+	caller := zapcore.NewEntryCaller(0, "x.go", 1, true)
+
+	before := Stats()
+	rl.Check(zapcore.Entry{Level: zapcore.ErrorLevel, Caller: caller, Message: "db down: a"}, &zapcore.CheckedEntry{})
+	if Stats() != before {
+		t.Fatalf("first entry from a call site should never be dropped")
+	}
+
+	// Same call site, different formatted message (as a burst of
+	// Errorf("db down: %v", err) would produce) must still be limited.
+	rl.Check(zapcore.Entry{Level: zapcore.ErrorLevel, Caller: caller, Message: "db down: b"}, &zapcore.CheckedEntry{})
+	if Stats() != before+1 {
+		t.Fatalf("expected burst with differing message but same caller to be rate-limited")
+	}
+}
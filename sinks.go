@@ -0,0 +1,187 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap/zapcore"
+)
+
+type registeredSink struct {
+	// core, when set, is used as-is instead of building one from
+	// ws/enc/lvl. Sinks that need the raw zapcore.Entry (e.g. NewSyslogCore,
+	// which maps Entry.Level to a syslog severity) register a Core
+	// directly rather than going through the generic ws/enc/lvl path.
+	core zapcore.Core
+	ws   zapcore.WriteSyncer
+	enc  zapcore.Encoder
+	lvl  zapcore.LevelEnabler
+}
+
+var (
+	sinkMu sync.Mutex
+	sinks  = map[string]registeredSink{}
+)
+
+// RegisterSink adds a named remote sink. ws does the actual write
+// (batching/retrying as needed), enc controls how log entries are encoded
+// before being handed to ws, and lvl decides which levels are forwarded to
+// this sink. Select it for a given Init call via that call's sinkNames
+// argument.
+func RegisterSink(name string, ws zapcore.WriteSyncer, enc zapcore.Encoder, lvl zapcore.LevelEnabler) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	sinks[name] = registeredSink{ws: ws, enc: enc, lvl: lvl}
+}
+
+// RegisterSinkCore adds a named remote sink backed by a fully custom
+// zapcore.Core, for sinks that need access to the raw zapcore.Entry (e.g.
+// its level) rather than just the encoded bytes a WriteSyncer sees.
+func RegisterSinkCore(name string, core zapcore.Core) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	sinks[name] = registeredSink{core: core}
+}
+
+// appendSinks adds a zapcore.Core for every name in sinkNames that was
+// registered with RegisterSink/RegisterSinkCore.
+func appendSinks(sinkNames []string, cores *[]zapcore.Core) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	for _, name := range sinkNames {
+		s, ok := sinks[name]
+		if !ok {
+			fmt.Printf("log: sink %q was not registered, skipping\n", name)
+			continue
+		}
+		if s.core != nil {
+			*cores = append(*cores, s.core)
+			continue
+		}
+		*cores = append(*cores, zapcore.NewCore(s.enc, s.ws, s.lvl))
+	}
+}
+
+// batchingSink is the common plumbing shared by the remote sinks below: a
+// bounded queue so a slow remote can't block the application goroutine
+// that's logging, a background flusher that batches writes, and retry with
+// exponential backoff so a transient outage doesn't drop everything.
+type batchingSink struct {
+	queue chan []byte
+	flush func([][]byte) error
+}
+
+func newBatchingSink(queueSize int, flush func([][]byte) error) *batchingSink {
+	s := &batchingSink{
+		queue: make(chan []byte, queueSize),
+		flush: flush,
+	}
+	go s.run()
+	return s
+}
+
+func (s *batchingSink) Write(p []byte) (int, error) {
+	line := make([]byte, len(p))
+	copy(line, p)
+	select {
+	case s.queue <- line:
+	default:
+		fmt.Println("log: remote sink queue full, dropping log line")
+	}
+	return len(p), nil
+}
+
+func (s *batchingSink) Sync() error {
+	return nil
+}
+
+func (s *batchingSink) run() {
+	const maxBatch = 100
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	batch := make([][]byte, 0, maxBatch)
+	for {
+		select {
+		case line := <-s.queue:
+			batch = append(batch, line)
+			if len(batch) >= maxBatch {
+				batch = s.flushWithBackoff(batch)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				batch = s.flushWithBackoff(batch)
+			}
+		}
+	}
+}
+
+func (s *batchingSink) flushWithBackoff(batch [][]byte) [][]byte {
+	backoff := 100 * time.Millisecond
+	for attempt := 0; attempt < 5; attempt++ {
+		if err := s.flush(batch); err == nil {
+			return batch[:0]
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	fmt.Printf("log: dropping %d log lines after repeated flush failures\n", len(batch))
+	return batch[:0]
+}
+
+// NewKafkaSink returns a WriteSyncer that ships log lines to a Kafka topic.
+func NewKafkaSink(brokers []string, topic string) zapcore.WriteSyncer {
+	w := &kafka.Writer{
+		Addr:     kafka.TCP(brokers...),
+		Topic:    topic,
+		Balancer: &kafka.LeastBytes{},
+	}
+	return newBatchingSink(1024, func(batch [][]byte) error {
+		msgs := make([]kafka.Message, len(batch))
+		for i, line := range batch {
+			msgs[i] = kafka.Message{Value: line}
+		}
+		return w.WriteMessages(context.Background(), msgs...)
+	})
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+// NewLokiSink returns a WriteSyncer that pushes log lines to a Loki server
+// via its HTTP push API, tagged with the given stream labels.
+func NewLokiSink(url string, labels map[string]string) zapcore.WriteSyncer {
+	client := &http.Client{Timeout: 5 * time.Second}
+	return newBatchingSink(1024, func(batch [][]byte) error {
+		now := strconv.FormatInt(time.Now().UnixNano(), 10)
+		values := make([][2]string, len(batch))
+		for i, line := range batch {
+			values[i] = [2]string{now, string(line)}
+		}
+		body, err := json.Marshal(lokiPushRequest{Streams: []lokiStream{{Stream: labels, Values: values}}})
+		if err != nil {
+			return err
+		}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("loki push: unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchingSinkWriteDropsWhenQueueFull(t *testing.T) {
+	s := &batchingSink{queue: make(chan []byte, 1)}
+
+	if _, err := s.Write([]byte("first")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	// Queue is now full; this write must be dropped rather than blocking the
+	// caller or evicting the first entry.
+	n, err := s.Write([]byte("second"))
+	if err != nil || n != len("second") {
+		t.Fatalf("Write should report success to the caller even when dropping, got n=%d err=%v", n, err)
+	}
+
+	if len(s.queue) != 1 {
+		t.Fatalf("expected queue to still hold exactly 1 line, got %d", len(s.queue))
+	}
+	if got := string(<-s.queue); got != "first" {
+		t.Fatalf("expected the original queued line to survive, got %q", got)
+	}
+}
+
+func TestBatchingSinkFlushWithBackoffRetriesUntilSuccess(t *testing.T) {
+	attempts := 0
+	s := &batchingSink{flush: func(batch [][]byte) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("transient failure")
+		}
+		return nil
+	}}
+
+	remaining := s.flushWithBackoff([][]byte{[]byte("x")})
+
+	if attempts != 3 {
+		t.Fatalf("expected flush to be retried until it succeeded, got %d attempts", attempts)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("expected batch to be cleared after a successful flush, got %d left", len(remaining))
+	}
+}
+
+func TestBatchingSinkFlushWithBackoffGivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	s := &batchingSink{flush: func(batch [][]byte) error {
+		attempts++
+		return errors.New("permanent failure")
+	}}
+
+	remaining := s.flushWithBackoff([][]byte{[]byte("x"), []byte("y")})
+
+	if attempts != 5 {
+		t.Fatalf("expected exactly 5 flush attempts before giving up, got %d", attempts)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("expected the batch to be dropped (truncated to empty) after giving up, got %d left", len(remaining))
+	}
+}
@@ -0,0 +1,184 @@
+//go:build !windows
+
+package log
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSyslogCore returns a zapcore.Core that ships log entries to a syslog
+// daemon over network/addr, e.g. ("udp", "localhost:514"), encoding each
+// entry with enc and forwarding only levels lvl enables. Register it with
+// RegisterSinkCore and select it by name via Init's sinkNames argument.
+//
+// This needs to be a Core rather than a WriteSyncer-backed sink (the way
+// NewKafkaSink/NewLokiSink work) because the syslog severity for a line has
+// to come from zapcore.Entry.Level: a WriteSyncer only ever sees the
+// already-rendered bytes, which would force severity to be guessed back out
+// of the rendered text and break on any message whose free-form content
+// happens to contain level-like words (e.g. Infof("upstream returned ERROR
+// code 500")), or on encoders that don't render the level as plain text at
+// all (e.g. JSON).
+func NewSyslogCore(network, addr string, enc zapcore.Encoder, lvl zapcore.LevelEnabler) (zapcore.Core, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO, "log")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogCore{
+		enc:  enc,
+		lvl:  lvl,
+		sink: newSyslogBatchingSink(1024, w),
+	}, nil
+}
+
+type syslogCore struct {
+	enc  zapcore.Encoder
+	lvl  zapcore.LevelEnabler
+	sink *syslogBatchingSink
+}
+
+func (c *syslogCore) Enabled(level zapcore.Level) bool {
+	return c.lvl.Enabled(level)
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone)
+	}
+	return &syslogCore{enc: clone, lvl: c.lvl, sink: c.sink}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimRight(buf.String(), "\n")
+	buf.Free()
+	c.sink.enqueue(syslogLine{priority: syslogPriority(ent.Level), msg: msg})
+	return nil
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
+
+// syslogPriority maps a zap level directly to a syslog severity, read off
+// the zapcore.Entry that produced it rather than recovered from rendered
+// text.
+func syslogPriority(level zapcore.Level) syslog.Priority {
+	switch {
+	case level >= zapcore.DPanicLevel:
+		return syslog.LOG_CRIT
+	case level == zapcore.ErrorLevel:
+		return syslog.LOG_ERR
+	case level == zapcore.WarnLevel:
+		return syslog.LOG_WARNING
+	case level == zapcore.DebugLevel:
+		return syslog.LOG_DEBUG
+	default:
+		return syslog.LOG_INFO
+	}
+}
+
+type syslogLine struct {
+	priority syslog.Priority
+	msg      string
+}
+
+// syslogBatchingSink mirrors batchingSink's bounded-queue/backoff plumbing,
+// but batches (priority, message) pairs instead of raw bytes: *syslog.Writer
+// picks the severity per call (w.Crit/w.Err/...), so the severity has to
+// travel alongside each line all the way to the write call instead of
+// being baked into an encoded []byte up front.
+type syslogBatchingSink struct {
+	queue chan syslogLine
+	w     *syslog.Writer
+}
+
+func newSyslogBatchingSink(queueSize int, w *syslog.Writer) *syslogBatchingSink {
+	s := &syslogBatchingSink{
+		queue: make(chan syslogLine, queueSize),
+		w:     w,
+	}
+	go s.run()
+	return s
+}
+
+func (s *syslogBatchingSink) enqueue(line syslogLine) {
+	select {
+	case s.queue <- line:
+	default:
+		fmt.Println("log: syslog sink queue full, dropping log line")
+	}
+}
+
+func (s *syslogBatchingSink) run() {
+	const maxBatch = 100
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	batch := make([]syslogLine, 0, maxBatch)
+	for {
+		select {
+		case line := <-s.queue:
+			batch = append(batch, line)
+			if len(batch) >= maxBatch {
+				batch = s.flushWithBackoff(batch)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				batch = s.flushWithBackoff(batch)
+			}
+		}
+	}
+}
+
+func (s *syslogBatchingSink) flushWithBackoff(batch []syslogLine) []syslogLine {
+	backoff := 100 * time.Millisecond
+	for attempt := 0; attempt < 5; attempt++ {
+		if err := s.flush(batch); err == nil {
+			return batch[:0]
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	fmt.Printf("log: dropping %d log lines after repeated flush failures\n", len(batch))
+	return batch[:0]
+}
+
+func (s *syslogBatchingSink) flush(batch []syslogLine) error {
+	for _, line := range batch {
+		if err := s.write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogBatchingSink) write(line syslogLine) error {
+	switch line.priority {
+	case syslog.LOG_CRIT:
+		return s.w.Crit(line.msg)
+	case syslog.LOG_ERR:
+		return s.w.Err(line.msg)
+	case syslog.LOG_WARNING:
+		return s.w.Warning(line.msg)
+	case syslog.LOG_DEBUG:
+		return s.w.Debug(line.msg)
+	default:
+		return s.w.Info(line.msg)
+	}
+}
@@ -0,0 +1,60 @@
+//go:build !windows
+
+package log
+
+import (
+	"log/syslog"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSyslogPriorityMapsEachZapLevel(t *testing.T) {
+	cases := []struct {
+		level zapcore.Level
+		want  syslog.Priority
+	}{
+		{zapcore.DebugLevel, syslog.LOG_DEBUG},
+		{zapcore.InfoLevel, syslog.LOG_INFO},
+		{zapcore.WarnLevel, syslog.LOG_WARNING},
+		{zapcore.ErrorLevel, syslog.LOG_ERR},
+		{zapcore.DPanicLevel, syslog.LOG_CRIT},
+		{zapcore.PanicLevel, syslog.LOG_CRIT},
+		{zapcore.FatalLevel, syslog.LOG_CRIT},
+	}
+	for _, c := range cases {
+		if got := syslogPriority(c.level); got != c.want {
+			t.Errorf("syslogPriority(%v) = %v, want %v", c.level, got, c.want)
+		}
+	}
+}
+
+// TestSyslogCoreWriteUsesEntryLevelNotMessageText checks that severity comes
+// from the entry's level: severity recovered by substring-matching the
+// rendered line text would misfire on an Info message whose free-form
+// content happens to contain a level-like word.
+func TestSyslogCoreWriteUsesEntryLevelNotMessageText(t *testing.T) {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	sink := &syslogBatchingSink{queue: make(chan syslogLine, 1)}
+	core := &syslogCore{
+		enc:  zapcore.NewConsoleEncoder(cfg),
+		lvl:  zapcore.InfoLevel,
+		sink: sink,
+	}
+
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Message: "upstream returned ERROR code 500"}
+	if err := core.Write(ent, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	select {
+	case line := <-sink.queue:
+		if line.priority != syslog.LOG_INFO {
+			t.Fatalf("expected LOG_INFO for an Info-level entry regardless of message text, got %v", line.priority)
+		}
+	default:
+		t.Fatal("expected a line to be enqueued")
+	}
+}